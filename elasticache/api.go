@@ -0,0 +1,83 @@
+package elasticache
+
+import "github.com/bradfitz/gomemcache/memcache"
+
+// client returns the current embedded *memcache.Client, guarded against a
+// concurrent rebuild by the topology refresher.
+func (c *Client) client() *memcache.Client {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return c.Client
+}
+
+// Get fetches the Item stored under key.
+func (c *Client) Get(key string) (*Item, error) {
+	mi, err := c.client().Get(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return fromMemcacheItem(mi), nil
+}
+
+// GetMulti fetches every Item stored under keys, keyed by Item.Key. A key
+// with no stored value is simply absent from the result, matching
+// gomemcache.Client.GetMulti.
+func (c *Client) GetMulti(keys []string) (map[string]*Item, error) {
+	items, err := c.client().GetMulti(keys)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make(map[string]*Item, len(items))
+	for k, mi := range items {
+		out[k] = fromMemcacheItem(mi)
+	}
+
+	return out, nil
+}
+
+// Add stores item only if the key does not already exist.
+func (c *Client) Add(item *Item) error {
+	return c.client().Add(toMemcacheItem(item))
+}
+
+// Replace stores item only if the key already exists.
+func (c *Client) Replace(item *Item) error {
+	return c.client().Replace(toMemcacheItem(item))
+}
+
+// CompareAndSwap stores item only if it hasn't been modified since it was
+// fetched. item must have come from Get or GetMulti.
+func (c *Client) CompareAndSwap(item *Item) error {
+	return c.client().CompareAndSwap(toMemcacheItem(item))
+}
+
+// CAS is an alias for CompareAndSwap, matching the shorter name most
+// memcache clients use for this operation.
+func (c *Client) CAS(item *Item) error {
+	return c.CompareAndSwap(item)
+}
+
+// Touch updates the expiration for the given key.
+func (c *Client) Touch(key string, seconds int32) error {
+	return c.client().Touch(key, seconds)
+}
+
+// Increment increments the value stored under key by delta, returning the
+// new value. The value must already exist and be decimal.
+func (c *Client) Increment(key string, delta uint64) (uint64, error) {
+	return c.client().Increment(key, delta)
+}
+
+// Decrement decrements the value stored under key by delta, returning the
+// new value. The value is floored at 0 and never wraps.
+func (c *Client) Decrement(key string, delta uint64) (uint64, error) {
+	return c.client().Decrement(key, delta)
+}
+
+// Delete removes the Item stored under key.
+func (c *Client) Delete(key string) error {
+	return c.client().Delete(key)
+}
@@ -0,0 +1,122 @@
+package elasticache
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awselasticache "github.com/aws/aws-sdk-go-v2/service/elasticache"
+	"github.com/aws/aws-sdk-go-v2/service/elasticache/types"
+)
+
+// AWSAPIDiscoverer discovers nodes via the AWS SDK's
+// elasticache.DescribeCacheClusters API (with ShowCacheNodeInfo=true),
+// rather than by speaking the memcached config-endpoint protocol. This
+// suits callers running inside EC2/EKS with IAM roles, who can discover
+// nodes by cluster id without pre-plumbing a configuration endpoint.
+type AWSAPIDiscoverer struct {
+	// API is the AWS SDK client used to describe the cluster. Construct it
+	// with elasticache.NewFromConfig, optionally overriding Region.
+	API *awselasticache.Client
+
+	// ClusterID is the ElastiCache cluster identifier to describe.
+	ClusterID string
+
+	// Tags, if non-empty, restricts discovery to clusters carrying all of
+	// the given key/value pairs.
+	Tags map[string]string
+}
+
+// Discover implements Discoverer. It makes a single DescribeCacheClusters
+// call and returns an error unless the cluster reports exactly
+// NumCacheNodes CacheNodes, since DescribeCacheClusters returns an incomplete node
+// list while the cluster is still provisioning. Discover does not itself
+// retry: NewWithDiscoverer retries the initial call with backoff, and the
+// background Instancer it starts keeps polling afterwards, so callers going
+// through either of those see a cluster that's still provisioning as a
+// delay rather than a hard failure.
+func (d AWSAPIDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	out, err := d.API.DescribeCacheClusters(ctx, &awselasticache.DescribeCacheClustersInput{
+		CacheClusterId:    aws.String(d.ClusterID),
+		ShowCacheNodeInfo: aws.Bool(true),
+	})
+	if err != nil {
+		logger.Printf("AWSAPIDiscoverer (%s): %s", d.ClusterID, err.Error())
+		return nil, err
+	}
+
+	if len(out.CacheClusters) == 0 {
+		return nil, fmt.Errorf("elasticache: cache cluster %q not found", d.ClusterID)
+	}
+
+	cluster := out.CacheClusters[0]
+
+	if len(d.Tags) > 0 {
+		matched, err := d.matchesTags(ctx, cluster)
+		if err != nil {
+			return nil, err
+		}
+		if !matched {
+			return nil, fmt.Errorf("elasticache: cache cluster %q does not match tag filter", d.ClusterID)
+		}
+	}
+
+	var wantNodes int32
+	if cluster.NumCacheNodes != nil {
+		wantNodes = *cluster.NumCacheNodes
+	}
+
+	if int32(len(cluster.CacheNodes)) != wantNodes {
+		return nil, fmt.Errorf("elasticache: cache cluster %q still provisioning: %d of %d nodes ready",
+			d.ClusterID, len(cluster.CacheNodes), wantNodes)
+	}
+
+	nodes := make([]Node, 0, len(cluster.CacheNodes))
+	for _, cn := range cluster.CacheNodes {
+		if cn.Endpoint == nil || cn.Endpoint.Address == nil || cn.Endpoint.Port == nil {
+			continue
+		}
+
+		host := *cn.Endpoint.Address
+		port := int(*cn.Endpoint.Port)
+
+		nodes = append(nodes, Node{
+			URL:  fmt.Sprintf("%s:%d", host, port),
+			Host: host,
+			IP:   host,
+			Port: port,
+		})
+	}
+
+	return nodes, nil
+}
+
+func (d AWSAPIDiscoverer) matchesTags(ctx context.Context, cluster types.CacheCluster) (bool, error) {
+	if cluster.ARN == nil {
+		return false, nil
+	}
+
+	out, err := d.API.ListTagsForResource(ctx, &awselasticache.ListTagsForResourceInput{
+		ResourceName: cluster.ARN,
+	})
+	if err != nil {
+		logger.Printf("AWSAPIDiscoverer (%s): list tags: %s", d.ClusterID, err.Error())
+		return false, err
+	}
+
+	got := make(map[string]string, len(out.TagList))
+	for _, tag := range out.TagList {
+		if tag.Key == nil || tag.Value == nil {
+			continue
+		}
+		got[*tag.Key] = *tag.Value
+	}
+
+	for k, v := range d.Tags {
+		if got[k] != v {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
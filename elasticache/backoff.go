@@ -0,0 +1,23 @@
+package elasticache
+
+import (
+	"math/rand"
+	"time"
+)
+
+// jitteredBackoff returns a delay for the given zero-based attempt count,
+// doubling from base and capped at max. When jitter is true the delay is
+// randomized within the second half of its range, so that many callers
+// backing off at once don't retry in lockstep.
+func jitteredBackoff(attempt int, base, max time.Duration, jitter bool) time.Duration {
+	delay := base * time.Duration(uint(1)<<uint(attempt))
+	if delay <= 0 || delay > max {
+		delay = max
+	}
+
+	if !jitter {
+		return delay
+	}
+
+	return delay/2 + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
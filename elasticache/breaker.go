@@ -0,0 +1,94 @@
+package elasticache
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrNodeCircuitOpen is returned by a context-aware operation when the
+// per-node circuit breaker for the node backing its key is open.
+var ErrNodeCircuitOpen = errors.New("elasticache: node circuit open")
+
+const (
+	breakerClosed   = "closed"
+	breakerOpen     = "open"
+	breakerHalfOpen = "half-open"
+)
+
+// circuitBreaker tracks consecutive failures against a single ElastiCache
+// node, failing fast once a threshold is crossed rather than letting every
+// caller wait out a timeout against a node that's already down.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mu          sync.Mutex
+	state       string
+	failures    int
+	openedAt    time.Time
+	halfOpenTry bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{threshold: threshold, cooldown: cooldown, state: breakerClosed}
+}
+
+// allow reports whether a call may proceed. Once the cooldown has elapsed
+// on an open breaker, a single half-open probe is let through.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerOpen:
+		if time.Since(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.halfOpenTry = true
+		return true
+	case breakerHalfOpen:
+		if b.halfOpenTry {
+			return false
+		}
+		b.halfOpenTry = true
+		return true
+	default:
+		return true
+	}
+}
+
+func (b *circuitBreaker) recordSuccess() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures = 0
+	b.halfOpenTry = false
+	b.state = breakerClosed
+}
+
+func (b *circuitBreaker) recordFailure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.halfOpenTry = false
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+		return
+	}
+
+	b.failures++
+	if b.failures >= b.threshold {
+		b.state = breakerOpen
+		b.openedAt = time.Now()
+	}
+}
+
+func (b *circuitBreaker) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.state
+}
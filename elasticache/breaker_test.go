@@ -0,0 +1,90 @@
+package elasticache
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestCircuitBreakerHalfOpenProbeFailureReopens guards against the breaker
+// wedging open forever: a half-open probe that fails (for any reason,
+// including the caller's own ctx expiring) must clear halfOpenTry and
+// reopen the breaker with a fresh cooldown, not leave it stuck half-open.
+func TestCircuitBreakerHalfOpenProbeFailureReopens(t *testing.T) {
+	b := newCircuitBreaker(1, 10*time.Millisecond)
+
+	b.recordFailure()
+	if got := b.String(); got != breakerOpen {
+		t.Fatalf("state = %q, want %q", got, breakerOpen)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("expected the half-open probe to be let through after cooldown")
+	}
+	if got := b.String(); got != breakerHalfOpen {
+		t.Fatalf("state = %q, want %q", got, breakerHalfOpen)
+	}
+
+	b.recordFailure() // the probe itself failed
+
+	if got := b.String(); got != breakerOpen {
+		t.Fatalf("a failed half-open probe left state %q, want %q", got, breakerOpen)
+	}
+	if b.halfOpenTry {
+		t.Fatal("halfOpenTry was left true after the probe failed; breaker would wedge open forever")
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if !b.allow() {
+		t.Fatal("breaker should allow a new probe once it has reopened and cooled down again")
+	}
+}
+
+// TestClientCallReopensBreakerWhenProbeTimesOut reproduces the scenario
+// from the bug report: a half-open probe whose own ctx expires mid-flight
+// must still report its outcome to the breaker, instead of call() early-
+// returning before recordFailure/recordSuccess runs.
+func TestClientCallReopensBreakerWhenProbeTimesOut(t *testing.T) {
+	c := &Client{
+		opts:     DefaultClientOptions.withDefaults(),
+		breakers: map[string]*circuitBreaker{},
+		selector: newServerList([]string{"127.0.0.1:11211"}),
+	}
+
+	const key = "some-key"
+
+	node, err := c.nodeForKey(key)
+	if err != nil {
+		t.Fatalf("nodeForKey: %v", err)
+	}
+
+	breaker := c.breakerFor(node)
+	breaker.threshold = 1
+	breaker.cooldown = 10 * time.Millisecond
+	breaker.recordFailure() // open the breaker, as a prior real failure would
+
+	time.Sleep(15 * time.Millisecond) // let the cooldown elapse
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	err = c.call(ctx, key, func() error {
+		time.Sleep(5 * time.Millisecond) // outlast ctx
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("call() error = %v, want context.DeadlineExceeded", err)
+	}
+
+	if got := breaker.String(); got != breakerOpen {
+		t.Fatalf("breaker state after a timed-out probe = %q, want %q (it must reopen, not wedge half-open)", got, breakerOpen)
+	}
+
+	time.Sleep(15 * time.Millisecond)
+	if !breaker.allow() {
+		t.Fatal("breaker should allow a fresh probe after reopening and cooling down again")
+	}
+}
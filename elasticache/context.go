@@ -0,0 +1,262 @@
+package elasticache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"time"
+
+	"github.com/bradfitz/gomemcache/memcache"
+	"github.com/thompsonlabs/go-elasticache/notify"
+)
+
+// SetContext is Set with a context deadline, retries, and per-node circuit
+// breaking.
+func (c *Client) SetContext(ctx context.Context, item *Item) error {
+	err := c.call(ctx, item.Key, func() error {
+		return c.client().Set(toMemcacheItem(item))
+	})
+
+	if err != nil {
+		c.publish(notify.Event{Type: notify.SetFailed, Time: time.Now(), Key: item.Key, Err: err.Error()})
+	} else {
+		c.publish(notify.Event{Type: notify.SetSucceeded, Time: time.Now(), Key: item.Key})
+	}
+
+	return err
+}
+
+// GetContext is Get with a context deadline, retries, and per-node circuit
+// breaking.
+func (c *Client) GetContext(ctx context.Context, key string) (*Item, error) {
+	var mi *memcache.Item
+
+	err := c.call(ctx, key, func() error {
+		var innerErr error
+		mi, innerErr = c.client().Get(key)
+		return innerErr
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return fromMemcacheItem(mi), nil
+}
+
+// AddContext is Add with a context deadline, retries, and per-node circuit
+// breaking.
+func (c *Client) AddContext(ctx context.Context, item *Item) error {
+	return c.call(ctx, item.Key, func() error {
+		return c.client().Add(toMemcacheItem(item))
+	})
+}
+
+// ReplaceContext is Replace with a context deadline, retries, and per-node
+// circuit breaking.
+func (c *Client) ReplaceContext(ctx context.Context, item *Item) error {
+	return c.call(ctx, item.Key, func() error {
+		return c.client().Replace(toMemcacheItem(item))
+	})
+}
+
+// CompareAndSwapContext is CompareAndSwap with a context deadline, retries,
+// and per-node circuit breaking.
+func (c *Client) CompareAndSwapContext(ctx context.Context, item *Item) error {
+	return c.call(ctx, item.Key, func() error {
+		return c.client().CompareAndSwap(toMemcacheItem(item))
+	})
+}
+
+// TouchContext is Touch with a context deadline, retries, and per-node
+// circuit breaking.
+func (c *Client) TouchContext(ctx context.Context, key string, seconds int32) error {
+	return c.call(ctx, key, func() error {
+		return c.client().Touch(key, seconds)
+	})
+}
+
+// IncrementContext is Increment with a context deadline, retries, and
+// per-node circuit breaking.
+func (c *Client) IncrementContext(ctx context.Context, key string, delta uint64) (uint64, error) {
+	var newValue uint64
+
+	err := c.call(ctx, key, func() error {
+		var innerErr error
+		newValue, innerErr = c.client().Increment(key, delta)
+		return innerErr
+	})
+
+	return newValue, err
+}
+
+// DecrementContext is Decrement with a context deadline, retries, and
+// per-node circuit breaking.
+func (c *Client) DecrementContext(ctx context.Context, key string, delta uint64) (uint64, error) {
+	var newValue uint64
+
+	err := c.call(ctx, key, func() error {
+		var innerErr error
+		newValue, innerErr = c.client().Decrement(key, delta)
+		return innerErr
+	})
+
+	return newValue, err
+}
+
+// DeleteContext is Delete with a context deadline, retries, and per-node
+// circuit breaking.
+func (c *Client) DeleteContext(ctx context.Context, key string) error {
+	return c.call(ctx, key, func() error {
+		return c.client().Delete(key)
+	})
+}
+
+// call runs fn, honouring ctx's deadline, retrying per c.opts.RetryPolicy
+// on a transient error, and - unless the breaker is disabled - failing
+// fast with ErrNodeCircuitOpen when the node backing key has an open
+// circuit.
+func (c *Client) call(ctx context.Context, key string, fn func() error) error {
+	var breaker *circuitBreaker
+	if !c.opts.DisableCircuitBreaker {
+		if node, err := c.nodeForKey(key); err == nil {
+			breaker = c.breakerFor(node)
+		}
+	}
+
+	attempts := c.opts.RetryPolicy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if breaker != nil && !breaker.allow() {
+			return ErrNodeCircuitOpen
+		}
+
+		err := runWithDeadline(ctx, fn)
+
+		if breaker != nil {
+			if err != nil {
+				breaker.recordFailure()
+			} else {
+				breaker.recordSuccess()
+			}
+		}
+
+		if err == nil {
+			return nil
+		}
+
+		// The caller's own deadline/cancellation is still recorded against
+		// the breaker above - a probe that only fails because it ran out of
+		// time looks identical to one that failed against a dead node - but
+		// it isn't worth retrying, since ctx is already done.
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(err, context.Canceled) {
+			return err
+		}
+
+		lastErr = err
+		if attempt == attempts-1 || !isRetryable(err) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(retryDelay(c.opts.RetryPolicy, attempt)):
+		}
+	}
+
+	return lastErr
+}
+
+// runWithDeadline runs fn on its own goroutine and returns ctx.Err() if ctx
+// is done first. fn's goroutine is left to finish in the background; the
+// underlying gomemcache call doesn't support cancellation mid-flight.
+func runWithDeadline(ctx context.Context, fn func() error) error {
+	done := make(chan error, 1)
+	go func() { done <- fn() }()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func isRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, memcache.ErrServerError) || errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return netErr.Temporary()
+	}
+
+	return false
+}
+
+func retryDelay(policy RetryPolicy, attempt int) time.Duration {
+	return jitteredBackoff(attempt, policy.BaseDelay, policy.MaxDelay, policy.Jitter)
+}
+
+// nodeForKey resolves the ElastiCache node that would serve key, using the
+// Client's own ServerList rather than gomemcache's internal one (which
+// isn't exposed).
+func (c *Client) nodeForKey(key string) (string, error) {
+	c.mu.RLock()
+	ss := c.selector
+	c.mu.RUnlock()
+
+	if ss == nil {
+		return "", errors.New("elasticache: no server selector configured")
+	}
+
+	addr, err := ss.PickServer(key)
+	if err != nil {
+		return "", err
+	}
+
+	return addr.String(), nil
+}
+
+func (c *Client) breakerFor(node string) *circuitBreaker {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+
+	b, ok := c.breakers[node]
+	if !ok {
+		b = newCircuitBreaker(c.opts.BreakerThreshold, c.opts.BreakerCooldown)
+		c.breakers[node] = b
+	}
+
+	return b
+}
+
+func (c *Client) dropBreaker(node string) {
+	c.breakerMu.Lock()
+	delete(c.breakers, node)
+	c.breakerMu.Unlock()
+}
+
+// CircuitState returns the current state ("closed", "half-open", "open")
+// of every node this Client has a circuit breaker for.
+func (c *Client) CircuitState() map[string]string {
+	c.breakerMu.Lock()
+	defer c.breakerMu.Unlock()
+
+	out := make(map[string]string, len(c.breakers))
+	for node, b := range c.breakers {
+		out[node] = b.String()
+	}
+
+	return out
+}
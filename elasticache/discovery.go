@@ -0,0 +1,41 @@
+package elasticache
+
+import (
+	"context"
+	"errors"
+	"os"
+)
+
+// Discoverer finds the current set of nodes backing an ElastiCache
+// cluster. ConfigEndpointDiscoverer and AWSAPIDiscoverer are the two
+// implementations shipped by this package; Client accepts any Discoverer
+// via NewWithDiscoverer.
+type Discoverer interface {
+	Discover(ctx context.Context) ([]Node, error)
+}
+
+// ConfigEndpointDiscoverer discovers nodes by speaking the memcached
+// "config get cluster" protocol to a single ElastiCache configuration
+// endpoint, read from the environment variable named by EnvVarName. This is
+// the discovery mechanism New and NewInstance use by default.
+type ConfigEndpointDiscoverer struct {
+	// EnvVarName is the environment variable holding the configuration
+	// endpoint. Defaults to ELASTICACHE_ENDPOINT if empty.
+	EnvVarName string
+}
+
+// Discover implements Discoverer.
+func (d ConfigEndpointDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	name := d.EnvVarName
+	if len(name) < 1 {
+		name = "ELASTICACHE_ENDPOINT"
+	}
+
+	endpoint := os.Getenv(name)
+	if len(endpoint) == 0 {
+		logger.Println("ElastiCache endpoint not set")
+		return nil, errors.New("ElastiCache endpoint not set")
+	}
+
+	return queryNodes(endpoint)
+}
@@ -2,7 +2,7 @@ package elasticache
 
 import (
 	"bufio"
-	"errors"
+	"context"
 	"fmt"
 	"io"
 	"log"
@@ -10,12 +10,19 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/bradfitz/gomemcache/memcache"
 	"github.com/integralist/go-findroot/find"
 	"github.com/thompsonlabs/go-elasticache/lister"
+	"github.com/thompsonlabs/go-elasticache/notify"
 )
 
+// DefaultRefreshInterval is how often a Client's background topology
+// Instancer re-runs the Client's Discoverer.
+const DefaultRefreshInterval = 30 * time.Second
+
 // Node is a single ElastiCache node
 type Node struct {
 	URL  string
@@ -24,32 +31,140 @@ type Node struct {
 	Port int
 }
 
-// Item embeds the memcache client's type of the same name
-type Item memcache.Item
-
 // Client embeds the memcache client so we can hide those details away
 type Client struct {
 	*memcache.Client
 	clusterNodeLister *lister.ClusterNodesKeyLister
+
+	// mu guards the embedded *memcache.Client, which is swapped out
+	// wholesale whenever the background Instancer observes a topology
+	// change.
+	mu sync.RWMutex
+
+	instancer *Instancer
+
+	handlersMu sync.Mutex
+	nodes      []Node
+	handlers   []func(Event)
+
+	notifiersMu sync.Mutex
+	notifiers   []*notifierWorker
+
+	// selector is kept in lockstep with the embedded *memcache.Client's own
+	// server list, so *Context operations can resolve which node a key
+	// routes to without gomemcache exposing that itself.
+	selector *memcache.ServerList
+
+	breakerMu sync.Mutex
+	breakers  map[string]*circuitBreaker
+	opts      ClientOptions
+
+	closeOnce sync.Once
+	done      chan struct{}
 }
 
-// Set abstracts the memcache client details away,
-// by copying over the values provided by the user into the Set method,
-// as coercing the custom Item type to the required memcache.Item type isn't possible.
-// Downside is if memcache client fields ever change, it'll introduce a break
+// Set abstracts the memcache client details away, converting the local
+// Item type to the memcache.Item type gomemcache requires.
 func (c *Client) Set(item *Item) error {
-	return c.Client.Set(&memcache.Item{
-		Key:        item.Key,
-		Value:      item.Value,
-		Expiration: item.Expiration,
+	err := c.client().Set(toMemcacheItem(item))
+
+	if err != nil {
+		c.publish(notify.Event{Type: notify.SetFailed, Time: time.Now(), Key: item.Key, Err: err.Error()})
+	} else {
+		c.publish(notify.Event{Type: notify.SetSucceeded, Time: time.Now(), Key: item.Key})
+	}
+
+	return err
+}
+
+// Nodes returns the set of ElastiCache nodes this Client is currently
+// configured against, as last observed by the background topology refresh.
+func (c *Client) Nodes() []Node {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	return append([]Node(nil), c.nodes...)
+}
+
+// OnTopologyChange registers fn to be called whenever the background
+// Instancer observes that the cluster's node set has changed, after the
+// underlying memcache server list has already been rebuilt to match. fn is
+// called synchronously from the topology watcher, so it should not block.
+func (c *Client) OnTopologyChange(fn func(Event)) {
+	c.handlersMu.Lock()
+	defer c.handlersMu.Unlock()
+
+	c.handlers = append(c.handlers, fn)
+}
+
+// Close stops this Client's background topology refresh and every
+// registered Notifier's worker goroutine. It does not close any underlying
+// memcache connections.
+func (c *Client) Close() {
+	if c.instancer != nil {
+		c.instancer.Close()
+	}
+
+	c.notifiersMu.Lock()
+	for _, w := range c.notifiers {
+		w.close()
+	}
+	c.notifiersMu.Unlock()
+
+	c.closeOnce.Do(func() { close(c.done) })
+}
+
+// startInstancer launches a background Instancer against d and wires its
+// events into this Client, rebuilding the memcache server list whenever the
+// node set changes.
+func (c *Client) startInstancer(d Discoverer, interval time.Duration, initial []Node) {
+	c.nodes = initial
+	c.instancer = NewInstancer(d, interval, initial)
+
+	events := make(chan Event, 8)
+	c.instancer.Subscribe(events)
+	c.instancer.OnError(func(err error) {
+		c.publish(notify.Event{Type: notify.DiscoveryFailed, Time: time.Now(), Err: err.Error()})
 	})
+
+	go c.watchTopology(events)
 }
 
-var logger *log.Logger
+func (c *Client) watchTopology(events <-chan Event) {
+	for {
+		select {
+		case <-c.done:
+			return
+		case ev := <-events:
+			urls := urlsFromNodes(ev.Nodes)
+
+			c.mu.Lock()
+			c.Client = memcache.New(urls...)
+			c.selector = newServerList(urls)
+			c.mu.Unlock()
+
+			c.handlersMu.Lock()
+			c.nodes = ev.Nodes
+			handlers := append([]func(Event){}, c.handlers...)
+			c.handlersMu.Unlock()
+
+			for _, fn := range handlers {
+				fn(ev)
+			}
+
+			now := time.Now()
+			for _, n := range ev.Added {
+				c.publish(notify.Event{Type: notify.NodeAdded, Time: now, Node: notifyNode(n)})
+			}
+			for _, n := range ev.Removed {
+				c.publish(notify.Event{Type: notify.NodeRemoved, Time: now, Node: notifyNode(n)})
+				c.dropBreaker(n.URL)
+			}
+		}
+	}
+}
 
-//new var to hold endpoint environment variable name this instance should use.
-//this allows each instance of this class to be associated with their own endpoint.
-var endpointEnvironmentVarName string
+var logger *log.Logger
 
 func init() {
 	logger = log.New(os.Stdout, "go-elasticache: ", log.Ldate|log.Ltime|log.Lshortfile)
@@ -71,50 +186,100 @@ func init() {
 	}
 }
 
-// New returns an instance of the memcache client
+// New returns an instance of the memcache client, discovering nodes from
+// the ELASTICACHE_ENDPOINT configuration endpoint.
 func New() (*Client, error) {
-	endpointEnvironmentVarName = "ELASTICACHE_ENDPOINT"
-	urls, err := clusterNodes()
-	if err != nil {
-		return &Client{Client: memcache.New()}, err
-	}
-
-	return &Client{Client: memcache.New(urls...),
-		clusterNodeLister: lister.NewClusterNodeKeyLister(urls)}, nil
+	return NewWithDiscoverer(ConfigEndpointDiscoverer{EnvVarName: "ELASTICACHE_ENDPOINT"})
 }
 
 // NewInstance - returns an instance of the memcache client, this alternative constructor
-//               allows an endpoint environment variable to be specified specific to this
-//               instance. Where a value is not provided the default value: ELASTICACHE_ENDPOINT
-//               will be used.
+//
+//	allows an endpoint environment variable to be specified specific to this
+//	instance. Where a value is not provided the default value: ELASTICACHE_ENDPOINT
+//	will be used.
 func NewInstance(endpointEnvVarName string) (*Client, error) {
 	if len(endpointEnvVarName) < 1 {
-		endpointEnvironmentVarName = "ELASTICACHE_ENDPOINT"
-	} else {
-		endpointEnvironmentVarName = endpointEnvVarName
+		endpointEnvVarName = "ELASTICACHE_ENDPOINT"
+	}
+
+	return NewWithDiscoverer(ConfigEndpointDiscoverer{EnvVarName: endpointEnvVarName})
+}
+
+// NewWithDiscoverer returns a Client whose initial node list, and whose
+// background topology refresh, come from d instead of the default
+// config-endpoint protocol. Use this to back a Client with AWSAPIDiscoverer,
+// or any other Discoverer implementation. opts configures retry and circuit
+// breaker behaviour for the *Context operations; DefaultClientOptions is
+// used if opts is omitted.
+//
+// The initial Discover call is retried with backoff for up to
+// opts.InitialDiscoveryTimeout, since a Discoverer like AWSAPIDiscoverer
+// errors rather than blocks while its cluster is still provisioning.
+func NewWithDiscoverer(d Discoverer, opts ...ClientOptions) (*Client, error) {
+	o := DefaultClientOptions
+	if len(opts) > 0 {
+		o = opts[0]
 	}
+	o = o.withDefaults()
 
-	urls, err := clusterNodes()
+	nodes, err := discoverWithRetry(d, o.InitialDiscoveryTimeout)
 	if err != nil {
-		return &Client{Client: memcache.New()}, err
+		return &Client{Client: memcache.New(), done: make(chan struct{}), opts: o, breakers: map[string]*circuitBreaker{}}, err
+	}
+
+	urls := urlsFromNodes(nodes)
+
+	c := &Client{
+		Client:            memcache.New(urls...),
+		clusterNodeLister: lister.NewClusterNodeKeyLister(urls),
+		selector:          newServerList(urls),
+		breakers:          map[string]*circuitBreaker{},
+		opts:              o,
+		done:              make(chan struct{}),
 	}
+	c.startInstancer(d, DefaultRefreshInterval, nodes)
+
+	return c, nil
+}
 
-	return &Client{Client: memcache.New(urls...),
-		clusterNodeLister: lister.NewClusterNodeKeyLister(urls)}, nil
+func newServerList(urls []string) *memcache.ServerList {
+	ss := new(memcache.ServerList)
+	ss.SetServers(urls...)
+	return ss
 }
 
-//Lists all keys stored accross all nodes in the Cluster.
+// discoverWithRetry calls d.Discover, backing off and trying again on error
+// until it succeeds or timeout elapses, so a Discoverer that errors while
+// its cluster is still provisioning doesn't fail NewWithDiscoverer outright.
+func discoverWithRetry(d Discoverer, timeout time.Duration) ([]Node, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		nodes, err := d.Discover(ctx)
+		if err == nil {
+			return nodes, nil
+		}
+		lastErr = err
+
+		select {
+		case <-ctx.Done():
+			return nil, lastErr
+		case <-time.After(jitteredBackoff(attempt, time.Second, 30*time.Second, true)):
+		}
+	}
+}
+
+// Lists all keys stored accross all nodes in the Cluster.
 func (c *Client) ListAllKeys() (*[]string, error) {
 
 	return c.clusterNodeLister.ListAllHostKeys()
 }
 
-func clusterNodes() ([]string, error) {
-	endpoint, err := elasticache()
-	if err != nil {
-		return nil, err
-	}
-
+// queryNodes dials endpoint directly and returns the nodes it reports. It
+// backs ConfigEndpointDiscoverer.
+func queryNodes(endpoint string) ([]Node, error) {
 	conn, err := net.Dial("tcp", endpoint)
 	if err != nil {
 		logger.Printf("Socket Dial (%s): %s", endpoint, err.Error())
@@ -130,24 +295,15 @@ func clusterNodes() ([]string, error) {
 		return nil, err
 	}
 
-	urls, err := parseURLs(response)
-	if err != nil {
-		return nil, err
-	}
-
-	return urls, nil
+	return parseNodeList(response)
 }
 
-func elasticache() (string, error) {
-	var endpoint string
-
-	endpoint = os.Getenv(endpointEnvironmentVarName)
-	if len(endpoint) == 0 {
-		logger.Println("ElastiCache endpoint not set")
-		return "", errors.New("ElastiCache endpoint not set")
+func urlsFromNodes(nodes []Node) []string {
+	urls := make([]string, len(nodes))
+	for i, n := range nodes {
+		urls[i] = n.URL
 	}
-
-	return endpoint, nil
+	return urls
 }
 
 func parseNodes(conn io.Reader) (string, error) {
@@ -176,8 +332,7 @@ func parseNodes(conn io.Reader) (string, error) {
 	return response, nil
 }
 
-func parseURLs(response string) ([]string, error) {
-	var urls []string
+func parseNodeList(response string) ([]Node, error) {
 	var nodes []Node
 
 	items := strings.Split(response, " ")
@@ -193,10 +348,9 @@ func parseURLs(response string) ([]string, error) {
 
 		node := Node{fmt.Sprintf("%s:%d", fields[1], port), fields[0], fields[1], port}
 		nodes = append(nodes, node)
-		urls = append(urls, node.URL)
 
 		logger.Printf("Host: %s, IP: %s, Port: %d, URL: %s", node.Host, node.IP, node.Port, node.URL)
 	}
 
-	return urls, nil
+	return nodes, nil
 }
@@ -0,0 +1,163 @@
+package elasticache
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Event describes a change in the set of nodes reported by a Discoverer,
+// as observed by an Instancer.
+type Event struct {
+	Added   []Node
+	Removed []Node
+	Nodes   []Node
+}
+
+// Instancer polls a Discoverer on an interval and pushes an Event to its
+// subscribers whenever the reported node set changes. It is modeled on the
+// common "instancer" push pattern: a single background goroutine owns the
+// polling and fans events out, rather than subscribers each polling
+// independently.
+type Instancer struct {
+	discoverer Discoverer
+	interval   time.Duration
+
+	mu          sync.Mutex
+	subs        []chan<- Event
+	errHandlers []func(error)
+	last        []Node
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+// NewInstancer starts polling d for the cluster node list every interval
+// and returns the Instancer. initial seeds the node set already known to
+// the caller (if any), so the first poll only reports a real topology
+// change rather than diffing against nothing. Call Subscribe to receive
+// topology Events, and Close to stop polling.
+func NewInstancer(d Discoverer, interval time.Duration, initial []Node) *Instancer {
+	in := &Instancer{
+		discoverer: d,
+		interval:   interval,
+		last:       initial,
+		done:       make(chan struct{}),
+	}
+
+	go in.loop()
+
+	return in
+}
+
+// Subscribe registers ch to receive topology Events. ch should be buffered
+// or drained promptly: Instancer does not block or retry on a full channel,
+// it drops the event and logs instead.
+func (in *Instancer) Subscribe(ch chan<- Event) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.subs = append(in.subs, ch)
+}
+
+// OnError registers fn to be called, from the polling goroutine, whenever a
+// discovery attempt fails.
+func (in *Instancer) OnError(fn func(error)) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	in.errHandlers = append(in.errHandlers, fn)
+}
+
+// Close stops the background polling goroutine. It is safe to call more
+// than once.
+func (in *Instancer) Close() {
+	in.closeOnce.Do(func() { close(in.done) })
+}
+
+func (in *Instancer) loop() {
+	timer := time.NewTimer(0) // run the first check immediately
+	defer timer.Stop()
+
+	attempt := 0
+	for {
+		select {
+		case <-in.done:
+			return
+		case <-timer.C:
+		}
+
+		nodes, err := in.discoverer.Discover(context.Background())
+		if err != nil {
+			attempt++
+			logger.Printf("Instancer: %s", err.Error())
+
+			in.mu.Lock()
+			handlers := append([]func(error){}, in.errHandlers...)
+			in.mu.Unlock()
+			for _, fn := range handlers {
+				fn(err)
+			}
+
+			timer.Reset(instancerBackoff(attempt))
+			continue
+		}
+
+		attempt = 0
+		in.publish(nodes)
+		timer.Reset(in.interval)
+	}
+}
+
+func (in *Instancer) publish(nodes []Node) {
+	in.mu.Lock()
+	added, removed := diffNodes(in.last, nodes)
+	in.last = nodes
+	subs := append([]chan<- Event{}, in.subs...)
+	in.mu.Unlock()
+
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	event := Event{Added: added, Removed: removed, Nodes: nodes}
+	for _, ch := range subs {
+		select {
+		case ch <- event:
+		default:
+			logger.Println("Instancer: subscriber channel full, dropping event")
+		}
+	}
+}
+
+// diffNodes compares two node sets by their full Host|IP|Port triple, so
+// that an IP flap on an otherwise unchanged hostname is still reported as a
+// change.
+func diffNodes(old, new []Node) (added, removed []Node) {
+	oldSet := make(map[Node]bool, len(old))
+	for _, n := range old {
+		oldSet[n] = true
+	}
+
+	newSet := make(map[Node]bool, len(new))
+	for _, n := range new {
+		newSet[n] = true
+		if !oldSet[n] {
+			added = append(added, n)
+		}
+	}
+
+	for _, n := range old {
+		if !newSet[n] {
+			removed = append(removed, n)
+		}
+	}
+
+	return added, removed
+}
+
+// instancerBackoff returns a jittered delay for the given failed-attempt
+// count, capped so a persistently unreachable endpoint doesn't get hammered.
+func instancerBackoff(attempt int) time.Duration {
+	return jitteredBackoff(attempt, time.Second, 30*time.Second, true)
+}
@@ -0,0 +1,172 @@
+package elasticache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestDiffNodes(t *testing.T) {
+	a := Node{URL: "a:1", Host: "a"}
+	b := Node{URL: "b:1", Host: "b"}
+	c := Node{URL: "c:1", Host: "c"}
+
+	cases := []struct {
+		name        string
+		old, new    []Node
+		wantAdded   []Node
+		wantRemoved []Node
+	}{
+		{"no change", []Node{a, b}, []Node{a, b}, nil, nil},
+		{"node added", []Node{a}, []Node{a, b}, []Node{b}, nil},
+		{"node removed", []Node{a, b}, []Node{a}, nil, []Node{b}},
+		{"node replaced", []Node{a, b}, []Node{a, c}, []Node{c}, []Node{b}},
+		{"from empty", nil, []Node{a}, []Node{a}, nil},
+		{"to empty", []Node{a}, nil, nil, []Node{a}},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			added, removed := diffNodes(tc.old, tc.new)
+			if !sameNodes(added, tc.wantAdded) {
+				t.Errorf("added = %v, want %v", added, tc.wantAdded)
+			}
+			if !sameNodes(removed, tc.wantRemoved) {
+				t.Errorf("removed = %v, want %v", removed, tc.wantRemoved)
+			}
+		})
+	}
+}
+
+func sameNodes(got, want []Node) bool {
+	if len(got) != len(want) {
+		return false
+	}
+	set := make(map[Node]bool, len(want))
+	for _, n := range want {
+		set[n] = true
+	}
+	for _, n := range got {
+		if !set[n] {
+			return false
+		}
+	}
+	return true
+}
+
+// fakeDiscoverer returns the results queued in results, one per call, and
+// holds on the last entry once exhausted.
+type fakeDiscoverer struct {
+	mu      sync.Mutex
+	results []fakeDiscoverResult
+	calls   int
+}
+
+type fakeDiscoverResult struct {
+	nodes []Node
+	err   error
+}
+
+func (d *fakeDiscoverer) Discover(ctx context.Context) ([]Node, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	i := d.calls
+	if i >= len(d.results) {
+		i = len(d.results) - 1
+	}
+	d.calls++
+
+	return d.results[i].nodes, d.results[i].err
+}
+
+func TestInstancerSeedsInitialNodesWithoutSpuriousEvent(t *testing.T) {
+	a := Node{URL: "a:1", Host: "a"}
+
+	d := &fakeDiscoverer{results: []fakeDiscoverResult{{nodes: []Node{a}}}}
+	in := NewInstancer(d, time.Hour, []Node{a})
+	defer in.Close()
+
+	events := make(chan Event, 1)
+	in.Subscribe(events)
+
+	// The fake discoverer reports the same node set passed in as initial,
+	// so the first poll must not report it as newly Added.
+	select {
+	case ev := <-events:
+		t.Fatalf("unexpected event on an unchanged node set: %+v", ev)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestInstancerPublishesOnTopologyChange(t *testing.T) {
+	a := Node{URL: "a:1", Host: "a"}
+	b := Node{URL: "b:1", Host: "b"}
+
+	d := &fakeDiscoverer{results: []fakeDiscoverResult{
+		{nodes: []Node{a}},
+		{nodes: []Node{a, b}},
+	}}
+	// Seed with the first poll's result, so the first event published is
+	// the change to [a, b].
+	in := NewInstancer(d, 5*time.Millisecond, []Node{a})
+	defer in.Close()
+
+	events := make(chan Event, 4)
+	in.Subscribe(events)
+
+	var ev Event
+	select {
+	case ev = <-events:
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for topology event")
+	}
+
+	if !sameNodes(ev.Added, []Node{b}) {
+		t.Fatalf("Added = %v, want [%v]", ev.Added, b)
+	}
+	if len(ev.Removed) != 0 {
+		t.Fatalf("Removed = %v, want none", ev.Removed)
+	}
+}
+
+func TestInstancerCallsErrorHandlerOnDiscoverFailure(t *testing.T) {
+	wantErr := errors.New("boom")
+	d := &fakeDiscoverer{results: []fakeDiscoverResult{{err: wantErr}}}
+
+	in := NewInstancer(d, time.Hour, nil)
+	defer in.Close()
+
+	got := make(chan error, 1)
+	in.OnError(func(err error) { got <- err })
+
+	select {
+	case err := <-got:
+		if !errors.Is(err, wantErr) {
+			t.Fatalf("error = %v, want %v", err, wantErr)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for error handler")
+	}
+}
+
+func TestInstancerCloseStopsPolling(t *testing.T) {
+	d := &fakeDiscoverer{results: []fakeDiscoverResult{{nodes: nil}}}
+	in := NewInstancer(d, time.Millisecond, nil)
+
+	in.Close()
+	in.Close() // must be safe to call twice
+
+	time.Sleep(20 * time.Millisecond)
+	d.mu.Lock()
+	calls := d.calls
+	d.mu.Unlock()
+
+	// Close should stop the loop promptly; allow the one in-flight poll
+	// that may have started just before Close.
+	if calls > 2 {
+		t.Fatalf("Instancer kept polling after Close: %d calls", calls)
+	}
+}
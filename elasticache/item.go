@@ -0,0 +1,61 @@
+package elasticache
+
+import "github.com/bradfitz/gomemcache/memcache"
+
+// Item is the local equivalent of gomemcache's Item. It is a distinct
+// struct, rather than a type conversion of memcache.Item, so CasID can be
+// named directly instead of forcing callers through the embedded client's
+// *memcache.Item.
+//
+// An Item returned by Get or GetMulti has CasID populated with the
+// token gomemcache captured for it, and a CompareAndSwap/CAS call with that
+// Item reuses it automatically. Set CasID directly only if your application
+// tracks CAS tokens itself; toMemcacheItem always sends CasID as given.
+type Item struct {
+	Key        string
+	Value      []byte
+	Flags      uint32
+	Expiration int32
+	CasID      uint64
+
+	src *memcache.Item
+}
+
+// toMemcacheItem and fromMemcacheItem are the single place gomemcache.Item
+// fields are copied to and from the local Item, so a future field addition
+// in gomemcache only needs to be threaded through here.
+
+func toMemcacheItem(item *Item) *memcache.Item {
+	if item.src != nil {
+		mi := *item.src
+		mi.Key = item.Key
+		mi.Value = item.Value
+		mi.Flags = item.Flags
+		mi.Expiration = item.Expiration
+		mi.CasID = item.CasID
+		return &mi
+	}
+
+	return &memcache.Item{
+		Key:        item.Key,
+		Value:      item.Value,
+		Flags:      item.Flags,
+		Expiration: item.Expiration,
+		CasID:      item.CasID,
+	}
+}
+
+func fromMemcacheItem(mi *memcache.Item) *Item {
+	if mi == nil {
+		return nil
+	}
+
+	return &Item{
+		Key:        mi.Key,
+		Value:      mi.Value,
+		Flags:      mi.Flags,
+		Expiration: mi.Expiration,
+		CasID:      mi.CasID,
+		src:        mi,
+	}
+}
@@ -0,0 +1,53 @@
+package elasticache
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/bradfitz/gomemcache/memcache"
+)
+
+// TestToMemcacheItemCopiesCasIDWithoutSrc guards against a regression where a
+// caller-constructed Item (no src, e.g. not returned by Get/GetMulti/CAS)
+// had its CasID silently dropped, sending CasID: 0 to gomemcache instead of
+// a manually tracked token.
+func TestToMemcacheItemCopiesCasIDWithoutSrc(t *testing.T) {
+	item := &Item{Key: "k", Value: []byte("v"), CasID: 42}
+
+	mi := toMemcacheItem(item)
+
+	if mi.CasID != 42 {
+		t.Fatalf("CasID = %d, want 42", mi.CasID)
+	}
+}
+
+// TestToMemcacheItemOverridesCasIDWithSrc covers the Get-then-CAS flow: the
+// CasID on src is already what fromMemcacheItem copied onto Item.CasID, but
+// an explicit override on Item.CasID must still take effect.
+func TestToMemcacheItemOverridesCasIDWithSrc(t *testing.T) {
+	item := fromMemcacheItem(&memcache.Item{Key: "k", Value: []byte("v"), CasID: 7})
+	item.CasID = 99
+
+	mi := toMemcacheItem(item)
+
+	if mi.CasID != 99 {
+		t.Fatalf("CasID = %d, want 99", mi.CasID)
+	}
+}
+
+func TestFromMemcacheItemCopiesFields(t *testing.T) {
+	mi := &memcache.Item{Key: "k", Value: []byte("v"), Flags: 3, Expiration: 60, CasID: 7}
+
+	item := fromMemcacheItem(mi)
+
+	if item.Key != mi.Key || !bytes.Equal(item.Value, mi.Value) || item.Flags != mi.Flags ||
+		item.Expiration != mi.Expiration || item.CasID != mi.CasID {
+		t.Fatalf("fromMemcacheItem(%+v) = %+v, fields don't match", mi, item)
+	}
+}
+
+func TestFromMemcacheItemNil(t *testing.T) {
+	if got := fromMemcacheItem(nil); got != nil {
+		t.Fatalf("fromMemcacheItem(nil) = %v, want nil", got)
+	}
+}
@@ -0,0 +1,129 @@
+package elasticache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/thompsonlabs/go-elasticache/notify"
+)
+
+// notifierBufferSize bounds the per-notifier event queue. Once full, the
+// oldest buffered event is dropped to make room for the newest.
+const notifierBufferSize = 64
+
+// notifierWorker owns a bounded, drop-oldest queue for a single registered
+// Notifier, so a slow or blocking sink never stalls the caller publishing
+// events.
+type notifierWorker struct {
+	name    string
+	n       notify.Notifier
+	events  chan notify.Event
+	dropped uint64
+
+	closeOnce sync.Once
+	done      chan struct{}
+}
+
+func newNotifierWorker(n notify.Notifier) *notifierWorker {
+	w := &notifierWorker{
+		name:   fmt.Sprintf("%T", n),
+		n:      n,
+		events: make(chan notify.Event, notifierBufferSize),
+		done:   make(chan struct{}),
+	}
+
+	go w.run()
+
+	return w
+}
+
+func (w *notifierWorker) run() {
+	for {
+		select {
+		case <-w.done:
+			return
+		case e := <-w.events:
+			// select picks at random when done and events are both ready,
+			// so check done again before delivering.
+			select {
+			case <-w.done:
+				return
+			default:
+			}
+			if err := w.n.Publish(e); err != nil {
+				logger.Printf("Notifier (%s): %s", w.name, err.Error())
+			}
+		}
+	}
+}
+
+// close stops this worker's background goroutine. It is safe to call more
+// than once.
+func (w *notifierWorker) close() {
+	w.closeOnce.Do(func() { close(w.done) })
+}
+
+// publish enqueues e, dropping the oldest queued event first if the buffer
+// is full rather than blocking the caller.
+func (w *notifierWorker) publish(e notify.Event) {
+	select {
+	case w.events <- e:
+		return
+	default:
+	}
+
+	select {
+	case <-w.events:
+		atomic.AddUint64(&w.dropped, 1)
+	default:
+	}
+
+	select {
+	case w.events <- e:
+	default:
+		atomic.AddUint64(&w.dropped, 1)
+	}
+}
+
+// RegisterNotifier attaches n so it receives NodeAdded, NodeRemoved,
+// SetSucceeded, SetFailed, and DiscoveryFailed events as this Client
+// operates. n.Publish is always called from a dedicated goroutine, never
+// from the call site that triggered the event; use notify.Multi to attach
+// several sinks as one Notifier.
+func (c *Client) RegisterNotifier(n notify.Notifier) {
+	w := newNotifierWorker(n)
+
+	c.notifiersMu.Lock()
+	c.notifiers = append(c.notifiers, w)
+	c.notifiersMu.Unlock()
+}
+
+// NotifierStats returns, per registered Notifier (keyed by its concrete
+// type), the count of events dropped because its buffer was full.
+func (c *Client) NotifierStats() map[string]uint64 {
+	c.notifiersMu.Lock()
+	defer c.notifiersMu.Unlock()
+
+	stats := make(map[string]uint64, len(c.notifiers))
+	for _, w := range c.notifiers {
+		stats[w.name] = atomic.LoadUint64(&w.dropped)
+	}
+
+	return stats
+}
+
+// publish fans e out to every registered Notifier's queue.
+func (c *Client) publish(e notify.Event) {
+	c.notifiersMu.Lock()
+	workers := append([]*notifierWorker{}, c.notifiers...)
+	c.notifiersMu.Unlock()
+
+	for _, w := range workers {
+		w.publish(e)
+	}
+}
+
+func notifyNode(n Node) *notify.Node {
+	return &notify.Node{URL: n.URL, Host: n.Host, IP: n.IP, Port: n.Port}
+}
@@ -0,0 +1,91 @@
+package elasticache
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/thompsonlabs/go-elasticache/notify"
+)
+
+// blockingNotifier blocks inside Publish until release is closed, so a test
+// can pause the worker goroutine mid-delivery and fill its queue.
+type blockingNotifier struct {
+	release chan struct{}
+	calls   uint64
+
+	mu  sync.Mutex
+	got []notify.Event
+}
+
+func (n *blockingNotifier) Publish(e notify.Event) error {
+	<-n.release
+	atomic.AddUint64(&n.calls, 1)
+
+	n.mu.Lock()
+	n.got = append(n.got, e)
+	n.mu.Unlock()
+
+	return nil
+}
+
+func TestNotifierWorkerDropsOldestWhenFull(t *testing.T) {
+	n := &blockingNotifier{release: make(chan struct{})}
+	w := newNotifierWorker(n)
+	defer w.close()
+
+	// run() immediately dequeues this first event and blocks inside
+	// Publish, so every subsequent publish queues up behind it.
+	w.publish(notify.Event{Key: "seed"})
+	time.Sleep(20 * time.Millisecond)
+
+	for i := 0; i < notifierBufferSize+1; i++ {
+		w.publish(notify.Event{Key: fmt.Sprintf("%d", i)})
+	}
+
+	if dropped := atomic.LoadUint64(&w.dropped); dropped != 1 {
+		t.Fatalf("dropped = %d, want 1", dropped)
+	}
+
+	close(n.release)
+}
+
+func TestClientNotifierStatsTracksDropped(t *testing.T) {
+	n := &blockingNotifier{release: make(chan struct{})}
+	c := &Client{}
+	c.RegisterNotifier(n)
+	defer func() {
+		close(n.release)
+	}()
+
+	c.publish(notify.Event{Key: "seed"})
+	time.Sleep(20 * time.Millisecond)
+
+	for i := 0; i < notifierBufferSize+2; i++ {
+		c.publish(notify.Event{Key: fmt.Sprintf("%d", i)})
+	}
+
+	stats := c.NotifierStats()
+	name := fmt.Sprintf("%T", n)
+	if stats[name] != 2 {
+		t.Fatalf("NotifierStats()[%q] = %d, want 2", name, stats[name])
+	}
+}
+
+func TestNotifierWorkerCloseStopsDelivery(t *testing.T) {
+	n := &blockingNotifier{release: make(chan struct{})}
+	close(n.release) // Publish never blocks for this test
+
+	w := newNotifierWorker(n)
+	w.close()
+	w.close() // must be safe to call twice
+
+	w.publish(notify.Event{Key: "after-close"})
+	time.Sleep(20 * time.Millisecond)
+
+	if calls := atomic.LoadUint64(&n.calls); calls != 0 {
+		t.Fatalf("Publish was called %d times after close, want 0", calls)
+	}
+}
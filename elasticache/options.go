@@ -0,0 +1,69 @@
+package elasticache
+
+import "time"
+
+// RetryPolicy controls how the *Context operations retry a transient
+// failure: memcache.ErrServerError, io.EOF, or a net.Error that reports
+// itself Temporary.
+type RetryPolicy struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+	Jitter      bool
+}
+
+// DefaultRetryPolicy is used by a Client constructed without explicit
+// ClientOptions.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	BaseDelay:   50 * time.Millisecond,
+	MaxDelay:    time.Second,
+	Jitter:      true,
+}
+
+// DefaultInitialDiscoveryTimeout bounds how long NewWithDiscoverer retries
+// the initial Discover call before giving up.
+const DefaultInitialDiscoveryTimeout = 2 * time.Minute
+
+// ClientOptions configures a Client's retry behaviour and per-node circuit
+// breaker. The zero value is not ready to use; pass it through
+// withDefaults, or simply omit ClientOptions when constructing a Client to
+// get DefaultClientOptions.
+type ClientOptions struct {
+	RetryPolicy RetryPolicy
+
+	DisableCircuitBreaker bool
+	BreakerThreshold      int
+	BreakerCooldown       time.Duration
+
+	// InitialDiscoveryTimeout bounds how long NewWithDiscoverer retries a
+	// Discoverer that errors on its first call (e.g. AWSAPIDiscoverer
+	// against a cluster that's still provisioning) before giving up.
+	InitialDiscoveryTimeout time.Duration
+}
+
+// DefaultClientOptions are applied when a Client is constructed without
+// explicit ClientOptions.
+var DefaultClientOptions = ClientOptions{
+	RetryPolicy:             DefaultRetryPolicy,
+	BreakerThreshold:        5,
+	BreakerCooldown:         30 * time.Second,
+	InitialDiscoveryTimeout: DefaultInitialDiscoveryTimeout,
+}
+
+// withDefaults fills in zero-valued fields of o from DefaultClientOptions.
+func (o ClientOptions) withDefaults() ClientOptions {
+	if o.RetryPolicy.MaxAttempts == 0 {
+		o.RetryPolicy = DefaultRetryPolicy
+	}
+	if o.BreakerThreshold == 0 {
+		o.BreakerThreshold = DefaultClientOptions.BreakerThreshold
+	}
+	if o.BreakerCooldown == 0 {
+		o.BreakerCooldown = DefaultClientOptions.BreakerCooldown
+	}
+	if o.InitialDiscoveryTimeout == 0 {
+		o.InitialDiscoveryTimeout = DefaultInitialDiscoveryTimeout
+	}
+	return o
+}
@@ -0,0 +1,36 @@
+// Package amqp is a notify.Notifier that publishes events as JSON to a
+// configured AMQP exchange/routing key.
+package amqp
+
+import (
+	"encoding/json"
+
+	"github.com/streadway/amqp"
+	"github.com/thompsonlabs/go-elasticache/notify"
+)
+
+// Notifier publishes each notify.Event as a JSON message.
+type Notifier struct {
+	channel    *amqp.Channel
+	exchange   string
+	routingKey string
+}
+
+// New returns a Notifier that publishes through ch to exchange, using
+// routingKey. ch must already be open; New does not declare the exchange.
+func New(ch *amqp.Channel, exchange, routingKey string) *Notifier {
+	return &Notifier{channel: ch, exchange: exchange, routingKey: routingKey}
+}
+
+// Publish implements notify.Notifier.
+func (n *Notifier) Publish(e notify.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	return n.channel.Publish(n.exchange, n.routingKey, false, false, amqp.Publishing{
+		ContentType: "application/json",
+		Body:        body,
+	})
+}
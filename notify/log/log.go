@@ -0,0 +1,25 @@
+// Package log is a notify.Notifier that writes events through a standard
+// library *log.Logger.
+package log
+
+import (
+	"log"
+
+	"github.com/thompsonlabs/go-elasticache/notify"
+)
+
+// Notifier writes each notify.Event as a single log line.
+type Notifier struct {
+	logger *log.Logger
+}
+
+// New returns a Notifier that writes through l.
+func New(l *log.Logger) *Notifier {
+	return &Notifier{logger: l}
+}
+
+// Publish implements notify.Notifier.
+func (n *Notifier) Publish(e notify.Event) error {
+	n.logger.Printf("type=%s time=%s key=%q node=%v err=%q", e.Type, e.Time.Format("2006-01-02T15:04:05Z07:00"), e.Key, e.Node, e.Err)
+	return nil
+}
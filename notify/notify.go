@@ -0,0 +1,63 @@
+// Package notify defines the structured events published by package
+// elasticache, and the Notifier sinks that consume them. Concrete sinks
+// live in subpackages (notify/log, notify/amqp, notify/redis,
+// notify/webhook) so that depending on elasticache never pulls in an AMQP
+// or Redis client transitively.
+package notify
+
+import "time"
+
+// Event Type values published by a Client.
+const (
+	NodeAdded       = "NodeAdded"
+	NodeRemoved     = "NodeRemoved"
+	SetSucceeded    = "SetSucceeded"
+	SetFailed       = "SetFailed"
+	DiscoveryFailed = "DiscoveryFailed"
+)
+
+// Node is a minimal, dependency-free description of an ElastiCache node.
+// It mirrors elasticache.Node field-for-field; a separate type avoids this
+// package importing elasticache, which elasticache.Client imports for the
+// Notifier interface below.
+type Node struct {
+	URL  string
+	Host string
+	IP   string
+	Port int
+}
+
+// Event is a structured occurrence published to a Notifier: a topology
+// change (NodeAdded, NodeRemoved, DiscoveryFailed) or the outcome of a
+// cache operation (SetSucceeded, SetFailed).
+type Event struct {
+	Type string
+	Time time.Time
+	Node *Node
+	Key  string
+	Err  string
+}
+
+// Notifier publishes Events to a sink - a log, a message queue, a webhook,
+// and so on. Publish is called from a per-notifier goroutine owned by the
+// Client, so a slow or blocking Notifier only ever stalls its own buffered
+// queue, never the caller.
+type Notifier interface {
+	Publish(Event) error
+}
+
+// Multi fans a single Publish call out to several Notifiers, so a Client
+// can be registered with one combined sink. Publish reports the first
+// error encountered, if any, but still attempts every Notifier.
+type Multi []Notifier
+
+// Publish implements Notifier.
+func (m Multi) Publish(e Event) error {
+	var firstErr error
+	for _, n := range m {
+		if err := n.Publish(e); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
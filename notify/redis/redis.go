@@ -0,0 +1,49 @@
+// Package redis is a notify.Notifier that delivers events to Redis, either
+// as list entries (LPUSH) or pub/sub messages (PUBLISH).
+package redis
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thompsonlabs/go-elasticache/notify"
+)
+
+// Mode selects how Notifier delivers events to Redis.
+type Mode int
+
+const (
+	// ModeList LPUSHes each event onto the Key list.
+	ModeList Mode = iota
+	// ModePubSub PUBLISHes each event to the Key channel.
+	ModePubSub
+)
+
+// Notifier publishes each notify.Event as JSON to Redis.
+type Notifier struct {
+	client *redis.Client
+	key    string
+	mode   Mode
+}
+
+// New returns a Notifier that publishes through client under key, per mode.
+func New(client *redis.Client, key string, mode Mode) *Notifier {
+	return &Notifier{client: client, key: key, mode: mode}
+}
+
+// Publish implements notify.Notifier.
+func (n *Notifier) Publish(e notify.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	if n.mode == ModePubSub {
+		return n.client.Publish(ctx, n.key, body).Err()
+	}
+
+	return n.client.LPush(ctx, n.key, body).Err()
+}
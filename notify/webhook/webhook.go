@@ -0,0 +1,47 @@
+// Package webhook is a notify.Notifier that POSTs events as JSON to a
+// configured URL.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/thompsonlabs/go-elasticache/notify"
+)
+
+// Notifier POSTs each notify.Event as a JSON body.
+type Notifier struct {
+	url    string
+	client *http.Client
+}
+
+// New returns a Notifier that POSTs to url. If client is nil,
+// http.DefaultClient is used.
+func New(url string, client *http.Client) *Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &Notifier{url: url, client: client}
+}
+
+// Publish implements notify.Notifier.
+func (n *Notifier) Publish(e notify.Event) error {
+	body, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	resp, err := n.client.Post(n.url, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}